@@ -0,0 +1,33 @@
+// Package cache provides a pluggable key/value cache used by service-b to
+// avoid re-hitting ViaCEP and the configured weather.Provider on every
+// request.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backend is a key/value store with per-entry TTLs. Implementations must be
+// safe for concurrent use.
+type Backend interface {
+	// Get returns the cached value for key, or ok=false if it is absent or
+	// expired.
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	// Set stores value under key for the given TTL.
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+}
+
+// New builds the Backend named by the CACHE_BACKEND env var. Supported names
+// are "memory" (the default) and "redis".
+func New(name string) (Backend, error) {
+	switch name {
+	case "", "memory":
+		return NewMemory(), nil
+	case "redis":
+		return NewRedis()
+	default:
+		return nil, fmt.Errorf("cache: unknown backend %q", name)
+	}
+}
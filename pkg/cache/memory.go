@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultMemoryCapacity bounds the LRU so a hot set of CEPs or cities can't
+// grow the in-memory cache without limit.
+const defaultMemoryCapacity = 10000
+
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// memoryBackend is an in-process LRU with per-entry TTLs. It satisfies
+// Backend and requires no external dependency, which makes it the default
+// for local development.
+type memoryBackend struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+// NewMemory builds an in-memory LRU Backend capped at defaultMemoryCapacity
+// entries.
+func NewMemory() Backend {
+	return &memoryBackend{
+		capacity: defaultMemoryCapacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (m *memoryBackend) Get(_ context.Context, key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return "", false, nil
+	}
+
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expiresAt) {
+		m.order.Remove(el)
+		delete(m.items, key)
+		return "", false, nil
+	}
+
+	m.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+func (m *memoryBackend) Set(_ context.Context, key string, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		el.Value.(*memoryEntry).value = value
+		el.Value.(*memoryEntry).expiresAt = time.Now().Add(ttl)
+		m.order.MoveToFront(el)
+		return nil
+	}
+
+	el := m.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+	m.items[key] = el
+
+	if m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		if oldest != nil {
+			m.order.Remove(oldest)
+			delete(m.items, oldest.Value.(*memoryEntry).key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestMemoryBackend(capacity int) *memoryBackend {
+	return &memoryBackend{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func TestMemoryBackendGetSetRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryBackend(10)
+
+	if err := m.Set(ctx, "k", "v", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, ok, err := m.Get(ctx, "k")
+	if err != nil || !ok || got != "v" {
+		t.Fatalf("Get = (%q, %v, %v), want (v, true, nil)", got, ok, err)
+	}
+}
+
+func TestMemoryBackendExpiresByTTL(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryBackend(10)
+
+	if err := m.Set(ctx, "k", "v", 10*time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok, err := m.Get(ctx, "k")
+	if err != nil || ok {
+		t.Fatalf("Get = (_, %v, %v), want (false, nil) after TTL expiry", ok, err)
+	}
+}
+
+func TestMemoryBackendEvictsOldestPastCapacity(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryBackend(2)
+
+	_ = m.Set(ctx, "a", "1", time.Minute)
+	_ = m.Set(ctx, "b", "2", time.Minute)
+	_ = m.Set(ctx, "c", "3", time.Minute)
+
+	if _, ok, _ := m.Get(ctx, "a"); ok {
+		t.Fatal("oldest entry 'a' should have been evicted past capacity")
+	}
+	if _, ok, _ := m.Get(ctx, "b"); !ok {
+		t.Fatal("'b' should still be cached")
+	}
+	if _, ok, _ := m.Get(ctx, "c"); !ok {
+		t.Fatal("'c' should still be cached")
+	}
+}
+
+func TestMemoryBackendGetRefreshesRecency(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryBackend(2)
+
+	_ = m.Set(ctx, "a", "1", time.Minute)
+	_ = m.Set(ctx, "b", "2", time.Minute)
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok, _ := m.Get(ctx, "a"); !ok {
+		t.Fatal("expected 'a' to be cached")
+	}
+
+	_ = m.Set(ctx, "c", "3", time.Minute)
+
+	if _, ok, _ := m.Get(ctx, "b"); ok {
+		t.Fatal("'b' should have been evicted as the least recently used entry")
+	}
+	if _, ok, _ := m.Get(ctx, "a"); !ok {
+		t.Fatal("'a' should still be cached after being touched")
+	}
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisBackend delegates to a shared Redis instance so multiple service-b
+// replicas can see each other's cached CEPs and weather lookups.
+type redisBackend struct {
+	client *redis.Client
+}
+
+// NewRedis builds a Backend backed by Redis, reading its address from
+// REDIS_ADDR (default "redis:6379").
+func NewRedis() (Backend, error) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "redis:6379"
+	}
+
+	return &redisBackend{
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+	}, nil
+}
+
+func (r *redisBackend) Get(ctx context.Context, key string) (string, bool, error) {
+	val, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return val, true, nil
+}
+
+func (r *redisBackend) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
@@ -0,0 +1,88 @@
+package prefetch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RefreshFunc re-runs the full CEP-to-weather pipeline for a single CEP,
+// warming whatever cache layers sit in front of it.
+type RefreshFunc func(ctx context.Context, cep string) error
+
+// Scheduler re-runs RefreshFunc for the hottest tracked CEPs a configurable
+// lead time before each refresh tick, so caches are warm by the time they'd
+// otherwise expire.
+type Scheduler struct {
+	cron    *cron.Cron
+	tracker *Tracker
+	refresh RefreshFunc
+	topN    int
+}
+
+// NewScheduler builds a Scheduler that prefetches the topN hottest CEPs seen
+// by tracker.
+func NewScheduler(tracker *Tracker, refresh RefreshFunc, topN int) *Scheduler {
+	return &Scheduler{
+		cron:    cron.New(),
+		tracker: tracker,
+		refresh: refresh,
+		topN:    topN,
+	}
+}
+
+// Start schedules a prefetch run every interval minus lead (or every interval
+// when lead is not shorter than it), so each run lands lead before the
+// entries written by the previous run would expire.
+func (s *Scheduler) Start(interval, lead time.Duration) error {
+	fireEvery := interval - lead
+	if fireEvery <= 0 {
+		fireEvery = interval
+	}
+
+	_, err := s.cron.AddFunc(fmt.Sprintf("@every %s", fireEvery), s.runOnce)
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	return nil
+}
+
+// Stop halts the scheduler. Any prefetch already in flight is left to
+// finish.
+func (s *Scheduler) Stop() {
+	s.cron.Stop()
+}
+
+// runOnce fires the hottest CEPs through refresh. It uses a detached,
+// root-spanned context so prefetch traces never get attributed to whichever
+// user request happened to trigger the cron tick. It waits for every refresh
+// to finish before ending the span, since any RecordError/AddEvent call
+// after span.End() is silently dropped.
+func (s *Scheduler) runOnce() {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(context.Background(), "prefetch-cep")
+	defer span.End()
+
+	hot := s.tracker.Top(s.topN)
+	span.SetAttributes(attribute.Int("prefetch.cep_count", len(hot)))
+
+	var wg sync.WaitGroup
+	for _, cep := range hot {
+		cep := cep
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.refresh(ctx, cep); err != nil {
+				span.RecordError(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,30 @@
+package prefetch
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSchedulerRunOnceWaitsForRefreshes guards against runOnce returning (and
+// ending its span) before the refresh goroutines it fired actually finish.
+func TestSchedulerRunOnceWaitsForRefreshes(t *testing.T) {
+	tracker := NewTracker(time.Hour)
+	tracker.Record("11111111")
+	tracker.Record("22222222")
+
+	var completed int32
+	refresh := func(ctx context.Context, cep string) error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&completed, 1)
+		return nil
+	}
+
+	s := NewScheduler(tracker, refresh, 10)
+	s.runOnce()
+
+	if got := atomic.LoadInt32(&completed); got != 2 {
+		t.Fatalf("runOnce returned before all refreshes completed: got %d, want 2", got)
+	}
+}
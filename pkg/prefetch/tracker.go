@@ -0,0 +1,81 @@
+// Package prefetch tracks which CEPs are hot and periodically re-runs the
+// CEP-to-weather pipeline for them so service-b's caches stay warm ahead of
+// real traffic.
+package prefetch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type hit struct {
+	cep string
+	at  time.Time
+}
+
+// Tracker records incoming CEPs and reports the hottest ones within a
+// sliding time window.
+type Tracker struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	hits []hit
+}
+
+// NewTracker builds a Tracker that only counts requests seen within the last
+// window (e.g. 30 or 60 minutes).
+func NewTracker(window time.Duration) *Tracker {
+	return &Tracker{window: window}
+}
+
+// Record notes that cep was requested now.
+func (t *Tracker) Record(cep string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hits = append(t.hits, hit{cep: cep, at: time.Now()})
+}
+
+// prune drops hits that have fallen out of the window. Callers must hold mu.
+func (t *Tracker) prune(now time.Time) {
+	cutoff := now.Add(-t.window)
+	i := 0
+	for ; i < len(t.hits); i++ {
+		if t.hits[i].at.After(cutoff) {
+			break
+		}
+	}
+	t.hits = t.hits[i:]
+}
+
+// Top returns up to n CEPs with the most hits in the current window, ordered
+// hottest first.
+func (t *Tracker) Top(n int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(time.Now())
+
+	counts := make(map[string]int, len(t.hits))
+	for _, h := range t.hits {
+		counts[h.cep]++
+	}
+
+	type ranked struct {
+		cep   string
+		count int
+	}
+	all := make([]ranked, 0, len(counts))
+	for cep, count := range counts {
+		all = append(all, ranked{cep, count})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].count > all[j].count })
+
+	if n > len(all) {
+		n = len(all)
+	}
+	top := make([]string, n)
+	for i := 0; i < n; i++ {
+		top[i] = all[i].cep
+	}
+	return top
+}
@@ -0,0 +1,44 @@
+package prefetch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackerTopOrdersByHitCount(t *testing.T) {
+	tr := NewTracker(time.Hour)
+	for i := 0; i < 3; i++ {
+		tr.Record("11111111")
+	}
+	tr.Record("22222222")
+
+	top := tr.Top(2)
+	if len(top) != 2 || top[0] != "11111111" || top[1] != "22222222" {
+		t.Fatalf("got %v, want [11111111 22222222]", top)
+	}
+}
+
+func TestTrackerTopTruncatesToN(t *testing.T) {
+	tr := NewTracker(time.Hour)
+	tr.Record("11111111")
+	tr.Record("22222222")
+	tr.Record("33333333")
+
+	top := tr.Top(1)
+	if len(top) != 1 {
+		t.Fatalf("got %d CEPs, want 1", len(top))
+	}
+}
+
+func TestTrackerTopDropsHitsOutsideWindow(t *testing.T) {
+	tr := NewTracker(50 * time.Millisecond)
+	tr.Record("11111111")
+
+	time.Sleep(100 * time.Millisecond)
+	tr.Record("22222222")
+
+	top := tr.Top(10)
+	if len(top) != 1 || top[0] != "22222222" {
+		t.Fatalf("got %v, want [22222222] (11111111 should have aged out)", top)
+	}
+}
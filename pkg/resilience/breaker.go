@@ -0,0 +1,184 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker is a failure-ratio breaker for a single host. Closed lets
+// all requests through while counting failures over a rolling window; once
+// the failure ratio crosses BreakerFailureRatio (given at least
+// BreakerMinRequests samples) it opens and short-circuits requests for
+// BreakerOpenTimeout before trying a single half-open probe.
+type circuitBreaker struct {
+	cfg Config
+
+	mu            sync.Mutex
+	state         breakerState
+	openedAt      time.Time
+	windowStart   time.Time
+	successes     int
+	failures      int
+	probeInFlight bool // true while half-open has let exactly one probe through
+}
+
+func newCircuitBreaker(cfg Config) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg, state: breakerClosed, windowStart: time.Now()}
+}
+
+// Allow reports whether a request should proceed, transitioning an open
+// breaker to half-open once its timeout has elapsed. Half-open lets exactly
+// one probe request through at a time - every other caller is denied until
+// that probe's outcome is recorded - rather than opening the floodgates to
+// every concurrent caller the instant the timeout elapses.
+func (b *circuitBreaker) Allow(span trace.Span) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerHalfOpen:
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	}
+
+	if time.Since(b.openedAt) < b.cfg.BreakerOpenTimeout {
+		return false
+	}
+
+	b.transition(breakerHalfOpen, span, "open-timeout-elapsed")
+	b.probeInFlight = true
+	return true
+}
+
+func (b *circuitBreaker) RecordSuccess(span trace.Span) { b.record(span, true) }
+func (b *circuitBreaker) RecordFailure(span trace.Span) { b.record(span, false) }
+
+func (b *circuitBreaker) record(span trace.Span, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		if success {
+			b.transition(breakerClosed, span, "probe-succeeded")
+		} else {
+			b.transition(breakerOpen, span, "probe-failed")
+		}
+		return
+	}
+
+	if time.Since(b.windowStart) > b.cfg.BreakerWindow {
+		b.successes, b.failures = 0, 0
+		b.windowStart = time.Now()
+	}
+
+	if success {
+		b.successes++
+	} else {
+		b.failures++
+	}
+
+	total := b.successes + b.failures
+	if total < b.cfg.BreakerMinRequests {
+		return
+	}
+
+	if float64(b.failures)/float64(total) >= b.cfg.BreakerFailureRatio {
+		b.transition(breakerOpen, span, "failure_ratio_exceeded")
+	}
+}
+
+// transition must be called with mu held.
+func (b *circuitBreaker) transition(to breakerState, span trace.Span, reason string) {
+	from := b.state
+	if from == to {
+		return
+	}
+
+	b.state = to
+	switch to {
+	case breakerOpen:
+		b.openedAt = time.Now()
+		b.probeInFlight = false
+	case breakerClosed:
+		b.successes, b.failures = 0, 0
+		b.windowStart = time.Now()
+		b.probeInFlight = false
+	case breakerHalfOpen:
+		b.successes, b.failures = 0, 0
+		b.windowStart = time.Now()
+	}
+
+	span.AddEvent("breaker.state_change", trace.WithAttributes(
+		attribute.String("breaker.from", from.String()),
+		attribute.String("breaker.to", to.String()),
+		attribute.String("breaker.trigger", reason),
+	))
+}
+
+func (b *circuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state.String()
+}
+
+// breakerRegistry keeps one circuitBreaker per host.
+type breakerRegistry struct {
+	cfg Config
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newBreakerRegistry(cfg Config) *breakerRegistry {
+	return &breakerRegistry{cfg: cfg, breakers: make(map[string]*circuitBreaker)}
+}
+
+func (r *breakerRegistry) get(host string) *circuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[host]
+	if !ok {
+		b = newCircuitBreaker(r.cfg)
+		r.breakers[host] = b
+	}
+	return b
+}
+
+func (r *breakerRegistry) states() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make(map[string]string, len(r.breakers))
+	for host, b := range r.breakers {
+		states[host] = b.State()
+	}
+	return states
+}
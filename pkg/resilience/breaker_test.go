@@ -0,0 +1,117 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func noopSpan() trace.Span {
+	return trace.SpanFromContext(context.Background())
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.BreakerMinRequests = 2
+	cfg.BreakerFailureRatio = 0.5
+	cfg.BreakerOpenTimeout = 20 * time.Millisecond
+	return cfg
+}
+
+func TestCircuitBreakerOpensOnFailureRatio(t *testing.T) {
+	b := newCircuitBreaker(testConfig())
+	span := noopSpan()
+
+	b.RecordFailure(span)
+	b.RecordFailure(span)
+
+	if got := b.State(); got != "open" {
+		t.Fatalf("state = %q, want open", got)
+	}
+	if b.Allow(span) {
+		t.Fatal("Allow returned true while breaker is open and within timeout")
+	}
+}
+
+func TestCircuitBreakerHalfOpensAfterTimeout(t *testing.T) {
+	b := newCircuitBreaker(testConfig())
+	span := noopSpan()
+
+	b.RecordFailure(span)
+	b.RecordFailure(span)
+
+	time.Sleep(30 * time.Millisecond)
+
+	if !b.Allow(span) {
+		t.Fatal("Allow returned false after open timeout elapsed")
+	}
+	if got := b.State(); got != "half-open" {
+		t.Fatalf("state = %q, want half-open", got)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeSuccessCloses(t *testing.T) {
+	b := newCircuitBreaker(testConfig())
+	span := noopSpan()
+
+	b.RecordFailure(span)
+	b.RecordFailure(span)
+	time.Sleep(30 * time.Millisecond)
+	b.Allow(span) // transitions to half-open, consumes the probe slot
+
+	b.RecordSuccess(span)
+
+	if got := b.State(); got != "closed" {
+		t.Fatalf("state = %q, want closed", got)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeFailureReopens(t *testing.T) {
+	b := newCircuitBreaker(testConfig())
+	span := noopSpan()
+
+	b.RecordFailure(span)
+	b.RecordFailure(span)
+	time.Sleep(30 * time.Millisecond)
+	b.Allow(span)
+
+	b.RecordFailure(span)
+
+	if got := b.State(); got != "open" {
+		t.Fatalf("state = %q, want open", got)
+	}
+}
+
+// TestCircuitBreakerHalfOpenAllowsOnlyOneProbe guards against every
+// concurrent caller being let through the instant the breaker transitions to
+// half-open - only one probe should be in flight at a time.
+func TestCircuitBreakerHalfOpenAllowsOnlyOneProbe(t *testing.T) {
+	b := newCircuitBreaker(testConfig())
+	span := noopSpan()
+
+	b.RecordFailure(span)
+	b.RecordFailure(span)
+	time.Sleep(30 * time.Millisecond)
+
+	const callers = 20
+	var allowed int32
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			if b.Allow(span) {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 1 {
+		t.Fatalf("allowed = %d concurrent callers through half-open, want exactly 1", allowed)
+	}
+}
@@ -0,0 +1,174 @@
+// Package resilience provides an http.RoundTripper that layers retry with
+// backoff, a per-host circuit breaker, and an optional rate limiter on top
+// of a plain transport. Both services wrap their downstream HTTP clients
+// with it instead of using otelhttp.NewTransport(http.DefaultTransport)
+// directly.
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// ErrCircuitOpen is returned by RoundTrip when the circuit breaker for the
+// request's host is open.
+var ErrCircuitOpen = errors.New("resilience: circuit breaker open")
+
+// Config tunes retry, breaker, and rate-limit behavior. Fields are used as
+// given; zero values do not fall back to DefaultConfig's values, except that
+// a MaxAttempts below 1 is treated as 1.
+type Config struct {
+	// MaxAttempts is the total number of tries, including the first. 1
+	// disables retries.
+	MaxAttempts     int
+	InitialInterval time.Duration
+	Multiplier      float64
+
+	BreakerFailureRatio float64
+	BreakerWindow       time.Duration
+	BreakerMinRequests  int
+	BreakerOpenTimeout  time.Duration
+
+	// RateLimit is requests per second; 0 disables the limiter.
+	RateLimit      float64
+	RateLimitBurst int
+}
+
+// DefaultConfig returns reasonable defaults: 3 attempts, 200ms initial
+// backoff doubling each retry, a breaker that opens once failures reach 50%
+// after it has seen at least 10 requests in a 30s window, and no rate limit.
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:     3,
+		InitialInterval: 200 * time.Millisecond,
+		Multiplier:      2,
+
+		BreakerFailureRatio: 0.5,
+		BreakerWindow:       30 * time.Second,
+		BreakerMinRequests:  10,
+		BreakerOpenTimeout:  15 * time.Second,
+	}
+}
+
+// Transport wraps inner with retry, a per-host circuit breaker, and an
+// optional rate limiter.
+type Transport struct {
+	inner    http.RoundTripper
+	cfg      Config
+	breakers *breakerRegistry
+	limiter  *rate.Limiter // nil when RateLimit is 0
+}
+
+// NewTransport builds an http.RoundTripper that applies cfg's resilience
+// policies around inner.
+func NewTransport(inner http.RoundTripper, cfg Config) *Transport {
+	t := &Transport{
+		inner:    inner,
+		cfg:      cfg,
+		breakers: newBreakerRegistry(cfg),
+	}
+	if cfg.RateLimit > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.RateLimitBurst)
+	}
+	return t
+}
+
+// BreakerStates returns the current breaker state for every host this
+// transport has seen a request for, keyed by host.
+func (t *Transport) BreakerStates() map[string]string {
+	return t.breakers.states()
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	span := trace.SpanFromContext(ctx)
+	host := req.URL.Host
+
+	breaker := t.breakers.get(host)
+	if !breaker.Allow(span) {
+		return nil, ErrCircuitOpen
+	}
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	maxAttempts := t.cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	interval := t.cfg.InitialInterval
+
+	var resp *http.Response
+	var err error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("resilience: cannot retry request with body: GetBody is nil")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("resilience: rewinding request body for retry: %w", err)
+			}
+			req.Body = body
+		}
+
+		resp, err = t.inner.RoundTrip(req)
+
+		retryable := err != nil || (resp != nil && resp.StatusCode >= 500)
+		if !retryable {
+			breaker.RecordSuccess(span)
+			return resp, err
+		}
+
+		breaker.RecordFailure(span)
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		delay := jitter(interval)
+		span.AddEvent("retry.attempt", trace.WithAttributes(
+			attribute.Int("retry.attempt_number", attempt),
+			attribute.String("retry.delay", delay.String()),
+			attribute.String("retry.trigger", retryReason(resp, err)),
+		))
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		interval = time.Duration(float64(interval) * t.cfg.Multiplier)
+	}
+
+	return resp, err
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// jitter returns d plus up to 50% extra, so retries from multiple callers
+// don't line up in lockstep.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func promhttpHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// REDMetrics is the shared set of RED (rate, errors, duration) instruments
+// used by both a service's Gin handlers and its outbound HTTP client.
+type REDMetrics struct {
+	requests metric.Int64Counter
+	duration metric.Float64Histogram
+	inFlight metric.Int64UpDownCounter
+}
+
+// NewREDMetrics registers the RED instruments against meter.
+func NewREDMetrics(meter metric.Meter) (*REDMetrics, error) {
+	requests, err := meter.Int64Counter(
+		"http.requests",
+		metric.WithDescription("Count of HTTP requests"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram(
+		"http.request.duration",
+		metric.WithDescription("HTTP request duration"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	inFlight, err := meter.Int64UpDownCounter(
+		"http.requests.in_flight",
+		metric.WithDescription("In-flight HTTP requests"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &REDMetrics{requests: requests, duration: duration, inFlight: inFlight}, nil
+}
+
+// GinMiddleware records RED metrics for every request, labeled by route and
+// status_code.
+func (m *REDMetrics) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := c.Request.Context()
+
+		m.inFlight.Add(ctx, 1)
+		defer m.inFlight.Add(ctx, -1)
+
+		c.Next()
+
+		attrs := metric.WithAttributes(
+			attribute.String("route", c.FullPath()),
+			attribute.Int("status_code", c.Writer.Status()),
+		)
+		m.requests.Add(ctx, 1, attrs)
+		m.duration.Record(ctx, float64(time.Since(start).Milliseconds()), attrs)
+	}
+}
+
+// ClientTransport wraps an http.RoundTripper, recording RED metrics for
+// outbound calls labeled by downstream (e.g. "viacep", "weatherapi",
+// "service-b") and status_code.
+type ClientTransport struct {
+	inner      http.RoundTripper
+	downstream string
+	metrics    *REDMetrics
+}
+
+// NewClientTransport wraps inner so every request through it is recorded
+// against metrics under the given downstream name.
+func NewClientTransport(inner http.RoundTripper, downstream string, metrics *REDMetrics) *ClientTransport {
+	return &ClientTransport{inner: inner, downstream: downstream, metrics: metrics}
+}
+
+func (t *ClientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	ctx := req.Context()
+
+	t.metrics.inFlight.Add(ctx, 1)
+	defer t.metrics.inFlight.Add(ctx, -1)
+
+	resp, err := t.inner.RoundTrip(req)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String("downstream", t.downstream),
+		attribute.Int("status_code", statusCode),
+	)
+	t.metrics.requests.Add(ctx, 1, attrs)
+	t.metrics.duration.Record(ctx, float64(time.Since(start).Milliseconds()), attrs)
+
+	return resp, err
+}
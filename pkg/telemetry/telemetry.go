@@ -0,0 +1,187 @@
+// Package telemetry wires up OpenTelemetry tracing and metrics for both
+// services from a single OTEL_EXPORTER env var, so neither one hardcodes a
+// particular backend.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// defaultShutdownTimeout bounds how long Shutdown waits for both providers
+// to flush when the caller doesn't specify one.
+const defaultShutdownTimeout = 5 * time.Second
+
+// Provider bundles the tracer and meter providers Init installs, plus the
+// Prometheus scrape handler when OTEL_EXPORTER=prometheus selected that
+// sink.
+type Provider struct {
+	TracerProvider *sdktrace.TracerProvider
+	MeterProvider  *sdkmetric.MeterProvider
+
+	// PrometheusHandler is non-nil only when OTEL_EXPORTER=prometheus; the
+	// caller is responsible for registering it at /metrics.
+	PrometheusHandler http.Handler
+}
+
+// Init reads OTEL_EXPORTER ("zipkin" (default), "otlp-http", "otlp-grpc",
+// "stdout", or "prometheus") and builds a TracerProvider and MeterProvider
+// against that backend, then installs both as global providers along with a
+// composite W3C tracecontext+baggage / B3 propagator so the services
+// interoperate with both Zipkin-style and modern OTel callers.
+func Init(ctx context.Context, serviceName string) (*Provider, error) {
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion("v1.0.0"),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: building resource: %w", err)
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+		b3.New(),
+	))
+
+	exporterName := os.Getenv("OTEL_EXPORTER")
+	if exporterName == "" {
+		exporterName = "zipkin"
+	}
+
+	// Prometheus only has a metrics story, so traces fall back to stdout
+	// rather than silently going nowhere.
+	traceExporterName := exporterName
+	if traceExporterName == "prometheus" {
+		traceExporterName = "stdout"
+	}
+
+	tp, err := newTracerProvider(ctx, traceExporterName, res)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+
+	mp, promHandler, err := newMeterProvider(ctx, exporterName, res)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetMeterProvider(mp)
+
+	return &Provider{
+		TracerProvider:    tp,
+		MeterProvider:     mp,
+		PrometheusHandler: promHandler,
+	}, nil
+}
+
+func newTracerProvider(ctx context.Context, exporterName string, res *resource.Resource) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch exporterName {
+	case "zipkin":
+		endpoint := os.Getenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "http://zipkin:9411/api/v2/spans"
+		}
+		exporter, err = zipkin.New(endpoint)
+	case "otlp-http":
+		exporter, err = otlptracehttp.New(ctx)
+	case "otlp-grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("telemetry: unknown OTEL_EXPORTER %q", exporterName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: building %s trace exporter: %w", exporterName, err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+func newMeterProvider(ctx context.Context, exporterName string, res *resource.Resource) (*sdkmetric.MeterProvider, http.Handler, error) {
+	if exporterName == "prometheus" {
+		promExporter, err := prometheus.New()
+		if err != nil {
+			return nil, nil, fmt.Errorf("telemetry: building prometheus exporter: %w", err)
+		}
+		mp := sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(promExporter),
+			sdkmetric.WithResource(res),
+		)
+		return mp, promhttpHandler(), nil
+	}
+
+	var exporter sdkmetric.Exporter
+	var err error
+
+	switch exporterName {
+	// zipkin has no metrics exporter of its own; OTLP/http is the most
+	// interoperable default for whatever collector sits behind it.
+	case "zipkin", "otlp-http":
+		exporter, err = otlpmetrichttp.New(ctx)
+	case "otlp-grpc":
+		exporter, err = otlpmetricgrpc.New(ctx)
+	case "stdout":
+		exporter, err = stdoutmetric.New()
+	default:
+		return nil, nil, fmt.Errorf("telemetry: unknown OTEL_EXPORTER %q", exporterName)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("telemetry: building %s metric exporter: %w", exporterName, err)
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(res),
+	)
+	return mp, nil, nil
+}
+
+// Shutdown flushes the tracer and meter providers, bounded by timeout (or
+// defaultShutdownTimeout if timeout is 0).
+func (p *Provider) Shutdown(ctx context.Context, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var errs []error
+	if err := p.TracerProvider.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.MeterProvider.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,218 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/resilience"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/telemetry"
+)
+
+// darkSkyProvider talks to a DarkSky-style forecast API. DarkSky addresses
+// location by lat/lon rather than city name, so loc.City is only used for
+// populating the Observation.
+type darkSkyProvider struct {
+	apiKey    string
+	client    *http.Client
+	transport *resilience.Transport
+}
+
+// NewDarkSky builds a Provider backed by a DarkSky-style endpoint, reading
+// its key from DARKSKY_KEY. clientMetrics, if non-nil, records RED metrics
+// for every call under the "darksky" downstream label.
+func NewDarkSky(clientMetrics *telemetry.REDMetrics) (Provider, error) {
+	apiKey := os.Getenv("DARKSKY_KEY")
+	if apiKey == "" {
+		return nil, errors.New("weather: DARKSKY_KEY not set")
+	}
+
+	transport := resilience.NewTransport(http.DefaultTransport, resilience.DefaultConfig())
+
+	return &darkSkyProvider{
+		apiKey:    apiKey,
+		transport: transport,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(instrumentedTransport(transport, "darksky", clientMetrics)),
+			Timeout:   10 * time.Second,
+		},
+	}, nil
+}
+
+// BreakerStates reports the DarkSky transport's per-host circuit breaker
+// state, for exposing at /debug/breakers.
+func (p *darkSkyProvider) BreakerStates() map[string]string {
+	return p.transport.BreakerStates()
+}
+
+func (p *darkSkyProvider) endpoint(loc Location) string {
+	return fmt.Sprintf("https://api.darksky.net/forecast/%s/%s,%s",
+		p.apiKey,
+		strconv.FormatFloat(loc.Lat, 'f', -1, 64),
+		strconv.FormatFloat(loc.Lon, 'f', -1, 64),
+	)
+}
+
+type darkSkyDataPoint struct {
+	Temperature      float64 `json:"temperature"`
+	ApparentTemp     float64 `json:"apparentTemperature"`
+	Humidity         float64 `json:"humidity"`
+	Pressure         float64 `json:"pressure"`
+	WindSpeed        float64 `json:"windSpeed"`
+	WindBearing      float64 `json:"windBearing"`
+	CloudCover       float64 `json:"cloudCover"`
+	Summary          string  `json:"summary"`
+	TemperatureHigh  float64 `json:"temperatureHigh"`
+	ApparentTempHigh float64 `json:"apparentTemperatureHigh"`
+}
+
+func (p *darkSkyProvider) Current(ctx context.Context, loc Location) (Observation, error) {
+	if loc.Lat == 0 && loc.Lon == 0 {
+		return Observation{}, ErrCoordinatesRequired
+	}
+
+	tracer := otel.Tracer("service-b")
+	endpoint := p.endpoint(loc)
+	ctx, span := tracer.Start(ctx, "darksky-current")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("weather.provider", "darksky"),
+		attribute.String("weather.endpoint", endpoint),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?exclude=minutely,hourly,daily,alerts,flags", nil)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("weather.response_code", int64(resp.StatusCode)))
+
+	if resp.StatusCode == http.StatusNotFound {
+		err := errors.New("city not found")
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	var w struct {
+		Currently darkSkyDataPoint `json:"currently"`
+	}
+	if err := json.Unmarshal(body, &w); err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	c := w.Currently
+	obs := Observation{
+		City:       loc.City,
+		TempC:      fahrenheitToCelsius(c.Temperature),
+		FeelsLikeC: fahrenheitToCelsius(c.ApparentTemp),
+		Humidity:   c.Humidity * 100,
+		PressureMB: c.Pressure,
+		WindKPH:    c.WindSpeed * 1.60934,
+		WindDegree: c.WindBearing,
+		CloudCover: c.CloudCover * 100,
+		Conditions: c.Summary,
+	}
+
+	span.SetAttributes(attribute.Float64("temperature.celsius", obs.TempC))
+
+	return obs, nil
+}
+
+func (p *darkSkyProvider) Forecast(ctx context.Context, loc Location, days int) ([]Observation, error) {
+	if loc.Lat == 0 && loc.Lon == 0 {
+		return nil, ErrCoordinatesRequired
+	}
+
+	tracer := otel.Tracer("service-b")
+	endpoint := p.endpoint(loc)
+	ctx, span := tracer.Start(ctx, "darksky-forecast")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("weather.provider", "darksky"),
+		attribute.String("weather.endpoint", endpoint),
+		attribute.Int("weather.forecast_days", days),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?exclude=currently,minutely,hourly,alerts,flags", nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("weather.response_code", int64(resp.StatusCode)))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var w struct {
+		Daily struct {
+			Data []darkSkyDataPoint `json:"data"`
+		} `json:"daily"`
+	}
+	if err := json.Unmarshal(body, &w); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if days > len(w.Daily.Data) {
+		days = len(w.Daily.Data)
+	}
+
+	observations := make([]Observation, 0, days)
+	for _, d := range w.Daily.Data[:days] {
+		observations = append(observations, Observation{
+			City:       loc.City,
+			TempC:      fahrenheitToCelsius(d.TemperatureHigh),
+			FeelsLikeC: fahrenheitToCelsius(d.ApparentTempHigh),
+			Humidity:   d.Humidity * 100,
+			PressureMB: d.Pressure,
+			WindKPH:    d.WindSpeed * 1.60934,
+			WindDegree: d.WindBearing,
+			CloudCover: d.CloudCover * 100,
+			Conditions: d.Summary,
+		})
+	}
+
+	return observations, nil
+}
+
+func fahrenheitToCelsius(f float64) float64 {
+	return (f - 32) / 1.8
+}
@@ -0,0 +1,240 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/resilience"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/telemetry"
+)
+
+// openWeatherMapProvider talks to api.openweathermap.org. It implements both
+// Provider and ForecastProvider via the One Call endpoint.
+type openWeatherMapProvider struct {
+	apiKey    string
+	client    *http.Client
+	transport *resilience.Transport
+}
+
+// NewOpenWeatherMap builds a Provider backed by OpenWeatherMap, reading its
+// key from OPENWEATHERMAP_KEY. clientMetrics, if non-nil, records RED
+// metrics for every call under the "openweathermap" downstream label.
+func NewOpenWeatherMap(clientMetrics *telemetry.REDMetrics) (Provider, error) {
+	apiKey := os.Getenv("OPENWEATHERMAP_KEY")
+	if apiKey == "" {
+		return nil, errors.New("weather: OPENWEATHERMAP_KEY not set")
+	}
+
+	transport := resilience.NewTransport(http.DefaultTransport, resilience.DefaultConfig())
+
+	return &openWeatherMapProvider{
+		apiKey:    apiKey,
+		transport: transport,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(instrumentedTransport(transport, "openweathermap", clientMetrics)),
+			Timeout:   10 * time.Second,
+		},
+	}, nil
+}
+
+// BreakerStates reports the OpenWeatherMap transport's per-host circuit
+// breaker state, for exposing at /debug/breakers.
+func (p *openWeatherMapProvider) BreakerStates() map[string]string {
+	return p.transport.BreakerStates()
+}
+
+func (p *openWeatherMapProvider) Current(ctx context.Context, loc Location) (Observation, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "openweathermap-current")
+	defer span.End()
+
+	endpoint := "https://api.openweathermap.org/data/2.5/weather"
+	span.SetAttributes(
+		attribute.String("weather.provider", "openweathermap"),
+		attribute.String("weather.endpoint", endpoint),
+	)
+
+	reqURL := endpoint + "?" + url.Values{
+		"appid": []string{p.apiKey},
+		"q":     []string{loc.City},
+		"units": []string{"metric"},
+	}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("weather.response_code", int64(resp.StatusCode)))
+
+	if resp.StatusCode == http.StatusNotFound {
+		err := errors.New("city not found")
+		span.RecordError(err)
+		return Observation{}, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		err := fmt.Errorf("openweathermap auth error: %d", resp.StatusCode)
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	var w struct {
+		Main struct {
+			Temp      float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			Humidity  float64 `json:"humidity"`
+			Pressure  float64 `json:"pressure"`
+		} `json:"main"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+			Deg   float64 `json:"deg"`
+		} `json:"wind"`
+		Clouds struct {
+			All float64 `json:"all"`
+		} `json:"clouds"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+	}
+	if err := json.Unmarshal(body, &w); err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	obs := Observation{
+		City:       loc.City,
+		TempC:      w.Main.Temp,
+		FeelsLikeC: w.Main.FeelsLike,
+		Humidity:   w.Main.Humidity,
+		PressureMB: w.Main.Pressure,
+		WindKPH:    w.Wind.Speed * 3.6,
+		WindDegree: w.Wind.Deg,
+		CloudCover: w.Clouds.All,
+	}
+	if len(w.Weather) > 0 {
+		obs.Conditions = w.Weather[0].Description
+	}
+
+	span.SetAttributes(attribute.Float64("temperature.celsius", obs.TempC))
+
+	return obs, nil
+}
+
+func (p *openWeatherMapProvider) Forecast(ctx context.Context, loc Location, days int) ([]Observation, error) {
+	if loc.Lat == 0 && loc.Lon == 0 {
+		return nil, ErrCoordinatesRequired
+	}
+
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "openweathermap-forecast")
+	defer span.End()
+
+	endpoint := "https://api.openweathermap.org/data/2.5/onecall"
+	span.SetAttributes(
+		attribute.String("weather.provider", "openweathermap"),
+		attribute.String("weather.endpoint", endpoint),
+		attribute.Int("weather.forecast_days", days),
+	)
+
+	reqURL := endpoint + "?" + url.Values{
+		"appid":   []string{p.apiKey},
+		"lat":     []string{strconv.FormatFloat(loc.Lat, 'f', -1, 64)},
+		"lon":     []string{strconv.FormatFloat(loc.Lon, 'f', -1, 64)},
+		"units":   []string{"metric"},
+		"exclude": []string{"current,minutely,hourly,alerts"},
+	}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("weather.response_code", int64(resp.StatusCode)))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var w struct {
+		Daily []struct {
+			Temp struct {
+				Day float64 `json:"day"`
+			} `json:"temp"`
+			FeelsLike struct {
+				Day float64 `json:"day"`
+			} `json:"feels_like"`
+			Humidity float64 `json:"humidity"`
+			Pressure float64 `json:"pressure"`
+			WindSpd  float64 `json:"wind_speed"`
+			WindDeg  float64 `json:"wind_deg"`
+			Clouds   float64 `json:"clouds"`
+			Weather  []struct {
+				Description string `json:"description"`
+			} `json:"weather"`
+		} `json:"daily"`
+	}
+	if err := json.Unmarshal(body, &w); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if days > len(w.Daily) {
+		days = len(w.Daily)
+	}
+
+	observations := make([]Observation, 0, days)
+	for _, d := range w.Daily[:days] {
+		obs := Observation{
+			City:       loc.City,
+			TempC:      d.Temp.Day,
+			FeelsLikeC: d.FeelsLike.Day,
+			Humidity:   d.Humidity,
+			PressureMB: d.Pressure,
+			WindKPH:    d.WindSpd * 3.6,
+			WindDegree: d.WindDeg,
+			CloudCover: d.Clouds,
+		}
+		if len(d.Weather) > 0 {
+			obs.Conditions = d.Weather[0].Description
+		}
+		observations = append(observations, obs)
+	}
+
+	return observations, nil
+}
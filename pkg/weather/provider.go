@@ -0,0 +1,118 @@
+// Package weather defines the WeatherProvider abstraction shared by service-b's
+// HTTP and gRPC handlers, along with the concrete backends that implement it.
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/resilience"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/telemetry"
+)
+
+// ErrForecastUnsupported describes a provider without a multi-day endpoint.
+// No provider in this package returns it: such providers do not implement
+// ForecastProvider, and callers detect that by type assertion.
+var ErrForecastUnsupported = errors.New("weather: provider does not support forecasts")
+
+// ErrCoordinatesRequired is returned by Current or Forecast when the provider
+// can only geocode by lat/lon and the Location it was given carries a city
+// name instead - e.g. a CEP-based lookup, since ViaCEP resolves a CEP to a city
+// name, not coordinates.
+var ErrCoordinatesRequired = errors.New("weather: provider requires lat/lon coordinates, not a city name")
+
+// Location identifies where an Observation should be taken for. City is
+// populated for CEP and city lookups (service-b resolves a CEP to a city
+// name before reaching a provider); a coordinate lookup carries only
+// Lat/Lon and leaves City empty.
+type Location struct {
+	City string
+	Lat  float64
+	Lon  float64
+}
+
+// Observation is the provider-agnostic weather reading. Every field is
+// populated from the subset of the external response schema that
+// WeatherAPI, OpenWeatherMap, and DarkSky all expose.
+type Observation struct {
+	City       string
+	TempC      float64
+	FeelsLikeC float64
+	Humidity   float64
+	PressureMB float64
+	WindKPH    float64
+	WindDegree float64
+	CloudCover float64
+	Conditions string
+}
+
+// Provider fetches the current observation for a Location.
+type Provider interface {
+	Current(ctx context.Context, loc Location) (Observation, error)
+}
+
+// ForecastProvider is implemented by providers that can also return a
+// multi-day forecast. Providers that only support current conditions should
+// not implement this interface; callers type-assert for it.
+type ForecastProvider interface {
+	Forecast(ctx context.Context, loc Location, days int) ([]Observation, error)
+}
+
+// BreakerObserver is implemented by providers whose downstream HTTP calls go
+// through a resilience.Transport, exposing its per-host circuit breaker
+// state for /debug/breakers.
+type BreakerObserver interface {
+	BreakerStates() map[string]string
+}
+
+// rateLimitEnv reads a requests-per-second rate limit from the named env
+// var, falling back to fallback if it is unset or unparseable.
+func rateLimitEnv(name string, fallback float64) float64 {
+	if v := os.Getenv(name); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+// New builds the Provider named by the WEATHER_PROVIDER env var. Supported
+// names are "weatherapi", "openweathermap", and "darksky". clientMetrics, if
+// non-nil, records RED metrics for every outbound call the provider makes,
+// labeled with its own name as the "downstream".
+func New(name string, clientMetrics *telemetry.REDMetrics) (Provider, error) {
+	switch name {
+	case "weatherapi":
+		return NewWeatherAPI(clientMetrics)
+	case "openweathermap":
+		return NewOpenWeatherMap(clientMetrics)
+	case "darksky":
+		return NewDarkSky(clientMetrics)
+	default:
+		return nil, fmt.Errorf("weather: unknown provider %q", name)
+	}
+}
+
+// locationQuery renders loc the way a "city or lat,lon" query-string
+// parameter expects: the city name when one was resolved, otherwise the
+// coordinates. Used by providers (WeatherAPI) whose search parameter
+// accepts either form.
+func locationQuery(loc Location) string {
+	if loc.City != "" {
+		return loc.City
+	}
+	return fmt.Sprintf("%g,%g", loc.Lat, loc.Lon)
+}
+
+// instrumentedTransport wraps transport with clientMetrics when non-nil,
+// otherwise returns it unchanged.
+func instrumentedTransport(transport *resilience.Transport, downstream string, clientMetrics *telemetry.REDMetrics) http.RoundTripper {
+	if clientMetrics == nil {
+		return transport
+	}
+	return telemetry.NewClientTransport(transport, downstream, clientMetrics)
+}
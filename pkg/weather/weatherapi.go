@@ -0,0 +1,156 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/resilience"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/telemetry"
+)
+
+// weatherAPIProvider talks to api.weatherapi.com. It only exposes current
+// conditions; it does not implement ForecastProvider.
+type weatherAPIProvider struct {
+	apiKey    string
+	client    *http.Client
+	transport *resilience.Transport
+}
+
+// NewWeatherAPI builds a Provider backed by WeatherAPI, reading its key from
+// WEATHERAPI_KEY. Its transport carries a client-side rate limiter tuned to
+// WeatherAPI's free-tier quota, configurable via WEATHERAPI_RATE_LIMIT
+// (requests per second; default 1). clientMetrics, if non-nil, records RED
+// metrics for every call under the "weatherapi" downstream label.
+func NewWeatherAPI(clientMetrics *telemetry.REDMetrics) (Provider, error) {
+	apiKey := os.Getenv("WEATHERAPI_KEY")
+	if apiKey == "" {
+		return nil, errors.New("weather: WEATHERAPI_KEY not set")
+	}
+
+	cfg := resilience.DefaultConfig()
+	cfg.RateLimit = rateLimitEnv("WEATHERAPI_RATE_LIMIT", 1)
+	cfg.RateLimitBurst = 1
+
+	transport := resilience.NewTransport(http.DefaultTransport, cfg)
+
+	return &weatherAPIProvider{
+		apiKey:    apiKey,
+		transport: transport,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(instrumentedTransport(transport, "weatherapi", clientMetrics)),
+			Timeout:   10 * time.Second,
+		},
+	}, nil
+}
+
+func (p *weatherAPIProvider) Current(ctx context.Context, loc Location) (Observation, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "weatherapi-current")
+	defer span.End()
+
+	endpoint := "https://api.weatherapi.com/v1/current.json"
+	span.SetAttributes(
+		attribute.String("weather.provider", "weatherapi"),
+		attribute.String("weather.endpoint", endpoint),
+	)
+
+	reqURL := endpoint + "?" + url.Values{
+		"key": []string{p.apiKey},
+		"q":   []string{locationQuery(loc)},
+		"aqi": []string{"no"},
+	}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("weather.response_code", int64(resp.StatusCode)))
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		err := fmt.Errorf("weatherapi auth error: %d", resp.StatusCode)
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	var w struct {
+		Current struct {
+			TempC     float64 `json:"temp_c"`
+			FeelsLike float64 `json:"feelslike_c"`
+			Humidity  float64 `json:"humidity"`
+			PressureM float64 `json:"pressure_mb"`
+			WindKPH   float64 `json:"wind_kph"`
+			WindDeg   float64 `json:"wind_degree"`
+			Cloud     float64 `json:"cloud"`
+			Condition struct {
+				Text string `json:"text"`
+			} `json:"condition"`
+		} `json:"current"`
+		Error *struct {
+			Code int    `json:"code"`
+			Msg  string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &w); err != nil {
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	if w.Error != nil {
+		if w.Error.Code == 1006 {
+			err := errors.New("city not found")
+			span.RecordError(err)
+			return Observation{}, err
+		}
+		err := fmt.Errorf("weatherapi error %d: %s", w.Error.Code, w.Error.Msg)
+		span.RecordError(err)
+		return Observation{}, err
+	}
+
+	obs := Observation{
+		City:       loc.City,
+		TempC:      w.Current.TempC,
+		FeelsLikeC: w.Current.FeelsLike,
+		Humidity:   w.Current.Humidity,
+		PressureMB: w.Current.PressureM,
+		WindKPH:    w.Current.WindKPH,
+		WindDegree: w.Current.WindDeg,
+		CloudCover: w.Current.Cloud,
+		Conditions: w.Current.Condition.Text,
+	}
+
+	span.SetAttributes(attribute.Float64("temperature.celsius", obs.TempC))
+
+	return obs, nil
+}
+
+// BreakerStates reports the WeatherAPI transport's per-host circuit breaker
+// state, for exposing at /debug/breakers.
+func (p *weatherAPIProvider) BreakerStates() map[string]string {
+	return p.transport.BreakerStates()
+}
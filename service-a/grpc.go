@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/proto/weatherpb"
+)
+
+// weatherServer implements weatherpb.WeatherServiceServer by validating the
+// location and forwarding the call to service-b over gRPC.
+type weatherServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+
+	client weatherpb.WeatherServiceClient
+}
+
+// resolveLocation validates a LocationRequest shape before it is forwarded
+// to service-b, which owns the actual CEP/city/coordinate resolution logic.
+func resolveLocation(req *weatherpb.LocationRequest) error {
+	switch loc := req.GetLocation().(type) {
+	case *weatherpb.LocationRequest_Cep:
+		if !validateCEP(loc.Cep) {
+			return fmt.Errorf("invalid zipcode")
+		}
+	case *weatherpb.LocationRequest_City:
+		if loc.City == "" {
+			return fmt.Errorf("city must not be empty")
+		}
+	case *weatherpb.LocationRequest_Coordinates:
+		// Any lat/lon pair is accepted as-is; service-b's provider validates it.
+	default:
+		return fmt.Errorf("location must be one of cep, city, or coordinates")
+	}
+	return nil
+}
+
+// fetchWeather calls service-b's WeatherService over gRPC for the given,
+// already-validated LocationRequest.
+func fetchWeather(ctx context.Context, client weatherpb.WeatherServiceClient, req *weatherpb.LocationRequest) (*weatherpb.WeatherReply, error) {
+	return client.GetWeather(ctx, req)
+}
+
+func (s *weatherServer) GetWeather(ctx context.Context, req *weatherpb.LocationRequest) (*weatherpb.WeatherReply, error) {
+	tracer := otel.Tracer("service-a")
+	ctx, span := tracer.Start(ctx, "grpc-get-weather")
+	defer span.End()
+
+	if err := resolveLocation(req); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("units", req.GetUnits().String()))
+
+	return fetchWeather(ctx, s.client, req)
+}
+
+// dialServiceB opens an otelgrpc-instrumented connection to service-b's gRPC
+// listener.
+func dialServiceB() (*grpc.ClientConn, error) {
+	target := os.Getenv("SERVICE_B_GRPC_ADDR")
+	if target == "" {
+		target = "service-b:9091"
+	}
+
+	return grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+}
+
+// runGRPCServer starts the gRPC listener on GRPC_PORT (default 9090) and
+// blocks until it stops serving.
+func runGRPCServer() error {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = "9090"
+	}
+
+	conn, err := dialServiceB()
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+	weatherpb.RegisterWeatherServiceServer(srv, &weatherServer{client: weatherpb.NewWeatherServiceClient(conn)})
+
+	return srv.Serve(lis)
+}
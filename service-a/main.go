@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
-	"os"
 	"regexp"
 	"time"
 
@@ -15,16 +15,20 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/zipkin"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/resilience"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/telemetry"
 )
 
 type CEPRequest struct {
 	CEP string `json:"cep"`
 }
 
+type ForecastRequest struct {
+	CEP  string `json:"cep"`
+	Days int    `json:"days"`
+}
+
 type WeatherResponse struct {
 	City  string  `json:"city"`
 	TempC float64 `json:"temp_C"`
@@ -36,45 +40,75 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
-func initTracer() *sdktrace.TracerProvider {
-	endpoint := os.Getenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT")
-	if endpoint == "" {
-		endpoint = "http://zipkin:9411/api/v2/spans"
-	}
+func validateCEP(cep string) bool {
+	matched, _ := regexp.MatchString(`^\d{8}$`, cep)
+	return matched
+}
 
-	exporter, err := zipkin.New(endpoint)
-	if err != nil {
-		panic(err)
+// serviceBTransport is shared across requests so its circuit breaker state
+// (and retry/rate-limit bookkeeping) persists between calls instead of
+// resetting on every request.
+var serviceBTransport = resilience.NewTransport(http.DefaultTransport, resilience.DefaultConfig())
+
+// clientMetrics records RED metrics for calls to service-b when
+// telemetry.Init selected a meter provider; it is set once in main before
+// any request handler runs.
+var clientMetrics *telemetry.REDMetrics
+
+// serviceBClient builds the HTTP client used to call service-b: an
+// otel-instrumented transport around the shared retry and per-host circuit
+// breaker transport, with (once main has run) RED metrics under the
+// "service-b" downstream label recorded in between.
+func serviceBClient() *http.Client {
+	var transport http.RoundTripper = serviceBTransport
+	if clientMetrics != nil {
+		transport = telemetry.NewClientTransport(serviceBTransport, "service-b", clientMetrics)
+	}
+	return &http.Client{
+		Transport: otelhttp.NewTransport(transport),
+		Timeout:   10 * time.Second,
 	}
-
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName("service-a"),
-			semconv.ServiceVersion("v1.0.0"),
-		)),
-	)
-
-	otel.SetTracerProvider(tp)
-	return tp
 }
 
-func validateCEP(cep string) bool {
-	matched, _ := regexp.MatchString(`^\d{8}$`, cep)
-	return matched
+// writeCircuitOpen responds with 503 and a Retry-After header when the
+// breaker guarding service-b has tripped, instead of a generic 500.
+func writeCircuitOpen(c *gin.Context) {
+	c.Header("Retry-After", "15")
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{Message: "service-b is currently unavailable"})
 }
 
 func main() {
-	tp := initTracer()
+	ctx := context.Background()
+
+	tel, err := telemetry.Init(ctx, "service-a")
+	if err != nil {
+		panic(err)
+	}
 	defer func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
+		if err := tel.Shutdown(context.Background(), 0); err != nil {
+			panic(err)
+		}
+	}()
+
+	redMetrics, err := telemetry.NewREDMetrics(tel.MeterProvider.Meter("service-a"))
+	if err != nil {
+		panic(err)
+	}
+	clientMetrics = redMetrics
+
+	go func() {
+		if err := runGRPCServer(); err != nil {
 			panic(err)
 		}
 	}()
 
 	r := gin.Default()
 	r.Use(otelgin.Middleware("service-a"))
+	r.Use(redMetrics.GinMiddleware())
+
+	if tel.PrometheusHandler != nil {
+		r.GET("/metrics", gin.WrapH(tel.PrometheusHandler))
+	}
 
 	r.POST("/cep", func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -102,10 +136,7 @@ func main() {
 		ctx, callSpan := tracer.Start(ctx, "call-service-b")
 		defer callSpan.End()
 
-		client := &http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-			Timeout:   10 * time.Second,
-		}
+		client := serviceBClient()
 
 		reqBody, _ := json.Marshal(req)
 		httpReq, err := http.NewRequestWithContext(ctx, "POST", "http://service-b:8081/weather", bytes.NewBuffer(reqBody))
@@ -119,6 +150,77 @@ func main() {
 
 		resp, err := client.Do(httpReq)
 		if err != nil {
+			if errors.Is(err, resilience.ErrCircuitOpen) {
+				callSpan.RecordError(err)
+				writeCircuitOpen(c)
+				return
+			}
+			callSpan.RecordError(err)
+			c.JSON(500, ErrorResponse{Message: "internal server error"})
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			callSpan.RecordError(err)
+			c.JSON(500, ErrorResponse{Message: "internal server error"})
+			return
+		}
+
+		callSpan.SetAttributes(
+			attribute.Int64("http.status_code", int64(resp.StatusCode)),
+		)
+
+		c.Data(resp.StatusCode, "application/json", body)
+	})
+
+	r.POST("/forecast", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tracer := otel.Tracer("service-a")
+
+		ctx, span := tracer.Start(ctx, "validate-forecast-cep")
+		defer span.End()
+
+		var req ForecastRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			span.RecordError(err)
+			c.JSON(422, ErrorResponse{Message: "invalid zipcode"})
+			return
+		}
+
+		if !validateCEP(req.CEP) {
+			c.JSON(422, ErrorResponse{Message: "invalid zipcode"})
+			return
+		}
+
+		span.SetAttributes(
+			attribute.String("cep", req.CEP),
+			attribute.Int("forecast.days", req.Days),
+		)
+
+		ctx, callSpan := tracer.Start(ctx, "call-service-b-forecast")
+		defer callSpan.End()
+
+		client := serviceBClient()
+
+		reqBody, _ := json.Marshal(req)
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", "http://service-b:8081/forecast", bytes.NewBuffer(reqBody))
+		if err != nil {
+			callSpan.RecordError(err)
+			c.JSON(500, ErrorResponse{Message: "internal server error"})
+			return
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			if errors.Is(err, resilience.ErrCircuitOpen) {
+				callSpan.RecordError(err)
+				writeCircuitOpen(c)
+				return
+			}
 			callSpan.RecordError(err)
 			c.JSON(500, ErrorResponse{Message: "internal server error"})
 			return
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/cache"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/prefetch"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/weather"
+)
+
+const (
+	defaultCEPCacheTTL      = 720 * time.Hour // ~30 days; CEP-to-city mappings essentially never change
+	defaultWeatherCacheTTL  = 5 * time.Minute
+	defaultPrefetchWindow   = 30 * time.Minute
+	defaultPrefetchTopN     = 10
+	defaultPrefetchLeadTime = 30 * time.Second
+)
+
+// cacheLayer sits in front of getCityFromCEP and the weather.Provider,
+// consulting a cache.Backend before falling through to the real lookups. It
+// also feeds the prefetch.Tracker used to keep hot CEPs warm.
+type cacheLayer struct {
+	backend     cache.Backend
+	backendName string
+	cepTTL      time.Duration
+	weatherTTL  time.Duration
+
+	hits   int64
+	misses int64
+
+	tracker *prefetch.Tracker
+}
+
+func durationEnv(name string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(name); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+func intEnv(name string, fallback int) int {
+	if v := os.Getenv(name); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func newCacheLayer() (*cacheLayer, error) {
+	backendName := os.Getenv("CACHE_BACKEND")
+	backend, err := cache.New(backendName)
+	if err != nil {
+		return nil, err
+	}
+	if backendName == "" {
+		backendName = "memory"
+	}
+
+	return &cacheLayer{
+		backend:     backend,
+		backendName: backendName,
+		cepTTL:      durationEnv("CEP_CACHE_TTL", defaultCEPCacheTTL),
+		weatherTTL:  durationEnv("WEATHER_CACHE_TTL", defaultWeatherCacheTTL),
+		tracker:     prefetch.NewTracker(durationEnv("PREFETCH_WINDOW", defaultPrefetchWindow)),
+	}, nil
+}
+
+func (c *cacheLayer) recordHit(hit bool) {
+	if hit {
+		atomic.AddInt64(&c.hits, 1)
+	} else {
+		atomic.AddInt64(&c.misses, 1)
+	}
+}
+
+// cityFromCEP wraps getCityFromCEP with the cache and records the CEP in the
+// prefetch tracker's hot-CEP window. Callers driven by real user traffic
+// should use this; the prefetch path uses lookupCityFromCEP directly so it
+// doesn't re-record its own hot CEPs as new hits.
+func (c *cacheLayer) cityFromCEP(ctx context.Context, cep string) (string, error) {
+	c.tracker.Record(cep)
+	return c.lookupCityFromCEP(ctx, cep)
+}
+
+// lookupCityFromCEP is cityFromCEP without the tracker side effect.
+func (c *cacheLayer) lookupCityFromCEP(ctx context.Context, cep string) (string, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "cep-cache-lookup")
+	defer span.End()
+	span.SetAttributes(attribute.String("cache.backend", c.backendName))
+
+	if city, ok, err := c.backend.Get(ctx, "cep:"+cep); err == nil && ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		c.recordHit(true)
+		return city, nil
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+	c.recordHit(false)
+
+	city, err := getCityFromCEP(ctx, cep)
+	if err != nil {
+		return "", err
+	}
+
+	_ = c.backend.Set(ctx, "cep:"+cep, city, c.cepTTL)
+	return city, nil
+}
+
+// currentWeather wraps provider.Current with the cache, keyed by city name.
+func (c *cacheLayer) currentWeather(ctx context.Context, provider weather.Provider, city string) (weather.Observation, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "weather-cache-lookup")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("cache.backend", c.backendName),
+		attribute.String("city", city),
+	)
+
+	if encoded, ok, err := c.backend.Get(ctx, "weather:"+city); err == nil && ok {
+		var obs weather.Observation
+		if jsonErr := json.Unmarshal([]byte(encoded), &obs); jsonErr == nil {
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			c.recordHit(true)
+			return obs, nil
+		}
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+	c.recordHit(false)
+
+	obs, err := provider.Current(ctx, weather.Location{City: city})
+	if err != nil {
+		return weather.Observation{}, err
+	}
+
+	if encoded, err := json.Marshal(obs); err == nil {
+		_ = c.backend.Set(ctx, "weather:"+city, string(encoded), c.weatherTTL)
+	}
+
+	return obs, nil
+}
+
+// refresh re-runs the CEP-to-weather pipeline for a single CEP, used by the
+// prefetch scheduler to keep hot CEPs warm ahead of real traffic. The city is
+// resolved through the CEP cache, but the weather is always fetched fresh
+// from the provider and written back with a new TTL, so an entry that is
+// about to expire gets replaced rather than served from cache. It uses
+// lookupCityFromCEP rather than cityFromCEP so that refreshing a hot
+// CEP doesn't itself count as a hit in the tracker's sliding window -
+// otherwise a CEP that enters the top-N would keep re-feeding its own hit
+// count and never age out even after real traffic for it stops.
+func (c *cacheLayer) refresh(provider weather.Provider) prefetch.RefreshFunc {
+	return func(ctx context.Context, cep string) error {
+		city, err := c.lookupCityFromCEP(ctx, cep)
+		if err != nil {
+			return err
+		}
+		obs, err := provider.Current(ctx, weather.Location{City: city})
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(obs)
+		if err != nil {
+			return err
+		}
+		return c.backend.Set(ctx, "weather:"+city, string(encoded), c.weatherTTL)
+	}
+}
+
+func (c *cacheLayer) statsHandler(ctx *gin.Context) {
+	ctx.JSON(200, gin.H{
+		"hits":     atomic.LoadInt64(&c.hits),
+		"misses":   atomic.LoadInt64(&c.misses),
+		"backend":  c.backendName,
+		"hot_ceps": c.tracker.Top(defaultPrefetchTopN),
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/weather"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/proto/weatherpb"
+)
+
+// weatherServer implements weatherpb.WeatherServiceServer on top of the same
+// weather.Provider the HTTP handlers use.
+type weatherServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+
+	provider weather.Provider
+}
+
+// resolveLocation turns a LocationRequest into the weather.Location the
+// configured weather.Provider expects, skipping the ViaCEP lookup whenever
+// the caller already supplied a city or coordinates. Coordinates are
+// carried through as Lat/Lon rather than collapsed into a City string, so
+// providers that address by lat/lon (DarkSky) or accept either (WeatherAPI)
+// both see the real values instead of (0, 0).
+func resolveLocation(ctx context.Context, req *weatherpb.LocationRequest) (weather.Location, error) {
+	switch loc := req.GetLocation().(type) {
+	case *weatherpb.LocationRequest_Cep:
+		if !validateCEP(loc.Cep) {
+			return weather.Location{}, fmt.Errorf("invalid zipcode")
+		}
+		city, err := getCityFromCEP(ctx, loc.Cep)
+		if err != nil {
+			return weather.Location{}, err
+		}
+		return weather.Location{City: city}, nil
+	case *weatherpb.LocationRequest_City:
+		return weather.Location{City: loc.City}, nil
+	case *weatherpb.LocationRequest_Coordinates:
+		return weather.Location{Lat: loc.Coordinates.Lat, Lon: loc.Coordinates.Lon}, nil
+	default:
+		return weather.Location{}, fmt.Errorf("location must be one of cep, city, or coordinates")
+	}
+}
+
+// fetchWeather calls the configured provider and shapes the result according
+// to the requested Units, populating only the temperature field(s) that unit
+// system calls for.
+func fetchWeather(ctx context.Context, provider weather.Provider, loc weather.Location, units weatherpb.Units) (*weatherpb.WeatherReply, error) {
+	obs, err := provider.Current(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &weatherpb.WeatherReply{
+		City:       obs.City,
+		FeelsLikeC: obs.FeelsLikeC,
+		Humidity:   obs.Humidity,
+		PressureMb: obs.PressureMB,
+		WindKph:    obs.WindKPH,
+		WindDegree: obs.WindDegree,
+		CloudCover: obs.CloudCover,
+		Conditions: obs.Conditions,
+	}
+
+	switch units {
+	case weatherpb.Units_IMPERIAL:
+		reply.TempF = celsiusToFahrenheit(obs.TempC)
+	case weatherpb.Units_STANDARD:
+		reply.TempK = celsiusToKelvin(obs.TempC)
+	default:
+		reply.TempC = obs.TempC
+	}
+
+	return reply, nil
+}
+
+func (s *weatherServer) GetWeather(ctx context.Context, req *weatherpb.LocationRequest) (*weatherpb.WeatherReply, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "grpc-get-weather")
+	defer span.End()
+
+	loc, err := resolveLocation(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("response.city", loc.City),
+		attribute.Float64("response.lat", loc.Lat),
+		attribute.Float64("response.lon", loc.Lon),
+	)
+
+	return fetchWeather(ctx, s.provider, loc, req.GetUnits())
+}
+
+// runGRPCServer starts the gRPC listener on GRPC_PORT (default 9091) and
+// blocks until it stops serving.
+func runGRPCServer(provider weather.Provider) error {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = "9091"
+	}
+
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+	weatherpb.RegisterWeatherServiceServer(srv, &weatherServer{provider: provider})
+
+	return srv.Serve(lis)
+}
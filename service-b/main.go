@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"os"
 	"regexp"
 	"time"
@@ -17,27 +16,61 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/zipkin"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/prefetch"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/resilience"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/telemetry"
+	"github.com/M-Zweibrucker/TraceWeatherCEP/pkg/weather"
 )
 
 type CEPRequest struct {
 	CEP string `json:"cep"`
 }
 
+type ForecastRequest struct {
+	CEP  string `json:"cep"`
+	Days int    `json:"days"`
+}
+
 type WeatherResponse struct {
-	City  string  `json:"city"`
-	TempC float64 `json:"temp_C"`
-	TempF float64 `json:"temp_F"`
-	TempK float64 `json:"temp_K"`
+	City       string  `json:"city"`
+	TempC      float64 `json:"temp_C"`
+	TempF      float64 `json:"temp_F"`
+	TempK      float64 `json:"temp_K"`
+	FeelsLikeC float64 `json:"feels_like_C"`
+	Humidity   float64 `json:"humidity"`
+	PressureMB float64 `json:"pressure_mb"`
+	WindKPH    float64 `json:"wind_kph"`
+	WindDegree float64 `json:"wind_degree"`
+	CloudCover float64 `json:"cloud_cover"`
+	Conditions string  `json:"conditions"`
+}
+
+type ForecastResponse struct {
+	City     string            `json:"city"`
+	Forecast []WeatherResponse `json:"forecast"`
 }
 
 type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+func observationToResponse(obs weather.Observation) WeatherResponse {
+	return WeatherResponse{
+		City:       obs.City,
+		TempC:      obs.TempC,
+		TempF:      celsiusToFahrenheit(obs.TempC),
+		TempK:      celsiusToKelvin(obs.TempC),
+		FeelsLikeC: obs.FeelsLikeC,
+		Humidity:   obs.Humidity,
+		PressureMB: obs.PressureMB,
+		WindKPH:    obs.WindKPH,
+		WindDegree: obs.WindDegree,
+		CloudCover: obs.CloudCover,
+		Conditions: obs.Conditions,
+	}
+}
+
 type ViaCEPResponse struct {
 	CEP         string `json:"cep"`
 	Logradouro  string `json:"logradouro"`
@@ -52,30 +85,6 @@ type ViaCEPResponse struct {
 	Erro        bool   `json:"erro"`
 }
 
-func initTracer() *sdktrace.TracerProvider {
-	endpoint := os.Getenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT")
-	if endpoint == "" {
-		endpoint = "http://zipkin:9411/api/v2/spans"
-	}
-
-	exporter, err := zipkin.New(endpoint)
-	if err != nil {
-		panic(err)
-	}
-
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName("service-b"),
-			semconv.ServiceVersion("v1.0.0"),
-		)),
-	)
-
-	otel.SetTracerProvider(tp)
-	return tp
-}
-
 func validateCEP(cep string) bool {
 	matched, _ := regexp.MatchString(`^\d{8}$`, cep)
 	return matched
@@ -89,6 +98,22 @@ func celsiusToKelvin(celsius float64) float64 {
 	return celsius + 273
 }
 
+// viaCEPTransport is shared across requests so its circuit breaker state
+// persists between calls instead of resetting on every request.
+var viaCEPTransport = resilience.NewTransport(http.DefaultTransport, resilience.DefaultConfig())
+
+// clientMetrics records RED metrics for outbound calls when telemetry.Init
+// selected a meter provider; it is set once in main before any request
+// handler runs.
+var clientMetrics *telemetry.REDMetrics
+
+func viaCEPRoundTripper() http.RoundTripper {
+	if clientMetrics == nil {
+		return viaCEPTransport
+	}
+	return telemetry.NewClientTransport(viaCEPTransport, "viacep", clientMetrics)
+}
+
 func getCityFromCEP(ctx context.Context, cep string) (string, error) {
 	tracer := otel.Tracer("service-b")
 	ctx, span := tracer.Start(ctx, "viacep-lookup")
@@ -99,7 +124,7 @@ func getCityFromCEP(ctx context.Context, cep string) (string, error) {
 	)
 
 	client := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
+		Transport: otelhttp.NewTransport(viaCEPRoundTripper()),
 		Timeout:   10 * time.Second,
 	}
 
@@ -142,101 +167,67 @@ func getCityFromCEP(ctx context.Context, cep string) (string, error) {
 	return viaCEPResp.Localidade, nil
 }
 
-func getWeather(ctx context.Context, city string) (float64, error) {
-	tracer := otel.Tracer("service-b")
-	ctx, span := tracer.Start(ctx, "weather-lookup")
-	defer span.End()
-
-	span.SetAttributes(
-		attribute.String("city", city),
-	)
-
-	client := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
-		Timeout:   10 * time.Second,
-	}
-
-	apiKey := os.Getenv("WEATHERAPI_KEY")
-	if apiKey == "" {
-		err := errors.New("WEATHERAPI_KEY not set")
-		span.RecordError(err)
-		return 0, err
-	}
-
-	weatherURL := "https://api.weatherapi.com/v1/current.json?" + url.Values{
-		"key": []string{apiKey},
-		"q":   []string{city},
-		"aqi": []string{"no"},
-	}.Encode()
+func main() {
+	ctx := context.Background()
 
-	wReq, err := http.NewRequestWithContext(ctx, "GET", weatherURL, nil)
+	tel, err := telemetry.Init(ctx, "service-b")
 	if err != nil {
-		span.RecordError(err)
-		return 0, err
+		panic(err)
 	}
+	defer func() {
+		if err := tel.Shutdown(context.Background(), 0); err != nil {
+			panic(err)
+		}
+	}()
 
-	wResp, err := client.Do(wReq)
+	redMetrics, err := telemetry.NewREDMetrics(tel.MeterProvider.Meter("service-b"))
 	if err != nil {
-		span.RecordError(err)
-		return 0, err
+		panic(err)
 	}
-	defer wResp.Body.Close()
+	clientMetrics = redMetrics
 
-	if wResp.StatusCode == http.StatusUnauthorized || wResp.StatusCode == http.StatusForbidden {
-		err := fmt.Errorf("weatherapi auth error: %d", wResp.StatusCode)
-		span.RecordError(err)
-		return 0, err
+	providerName := os.Getenv("WEATHER_PROVIDER")
+	if providerName == "" {
+		providerName = "weatherapi"
 	}
-
-	wBody, err := io.ReadAll(wResp.Body)
+	weatherProvider, err := weather.New(providerName, redMetrics)
 	if err != nil {
-		span.RecordError(err)
-		return 0, err
+		panic(err)
 	}
 
-	var w struct {
-		Current struct {
-			TempC float64 `json:"temp_c"`
-		} `json:"current"`
-		Error *struct {
-			Code int    `json:"code"`
-			Msg  string `json:"message"`
-		} `json:"error"`
-	}
-	if err := json.Unmarshal(wBody, &w); err != nil {
-		span.RecordError(err)
-		return 0, err
+	cacheLayer, err := newCacheLayer()
+	if err != nil {
+		panic(err)
 	}
 
-	if w.Error != nil {
-		if w.Error.Code == 1006 {
-			err := errors.New("city not found")
-			span.RecordError(err)
-			return 0, err
-		}
-		err := fmt.Errorf("weatherapi error %d: %s", w.Error.Code, w.Error.Msg)
-		span.RecordError(err)
-		return 0, err
+	scheduler := prefetch.NewScheduler(cacheLayer.tracker, cacheLayer.refresh(weatherProvider), defaultPrefetchTopN)
+	if err := scheduler.Start(cacheLayer.weatherTTL, durationEnv("PREFETCH_LEAD_TIME", defaultPrefetchLeadTime)); err != nil {
+		panic(err)
 	}
+	defer scheduler.Stop()
 
-	tempC := w.Current.TempC
-	span.SetAttributes(
-		attribute.Float64("temperature.celsius", tempC),
-	)
-
-	return tempC, nil
-}
-
-func main() {
-	tp := initTracer()
-	defer func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
+	go func() {
+		if err := runGRPCServer(weatherProvider); err != nil {
 			panic(err)
 		}
 	}()
 
 	r := gin.Default()
 	r.Use(otelgin.Middleware("service-b"))
+	r.Use(redMetrics.GinMiddleware())
+
+	if tel.PrometheusHandler != nil {
+		r.GET("/metrics", gin.WrapH(tel.PrometheusHandler))
+	}
+
+	r.GET("/debug/cache/stats", cacheLayer.statsHandler)
+	r.GET("/debug/breakers", func(c *gin.Context) {
+		breakers := gin.H{"viacep": viaCEPTransport.BreakerStates()}
+		if observer, ok := weatherProvider.(weather.BreakerObserver); ok {
+			breakers[providerName] = observer.BreakerStates()
+		}
+		c.JSON(200, breakers)
+	})
 
 	r.POST("/weather", func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -261,7 +252,7 @@ func main() {
 			attribute.String("cep", req.CEP),
 		)
 
-		city, err := getCityFromCEP(ctx, req.CEP)
+		city, err := cacheLayer.cityFromCEP(ctx, req.CEP)
 		if err != nil {
 			if err.Error() == "CEP not found" {
 				c.JSON(404, ErrorResponse{Message: "can not find zipcode"})
@@ -272,8 +263,12 @@ func main() {
 			return
 		}
 
-		tempC, err := getWeather(ctx, city)
+		obs, err := cacheLayer.currentWeather(ctx, weatherProvider, city)
 		if err != nil {
+			if errors.Is(err, weather.ErrCoordinatesRequired) {
+				c.JSON(http.StatusNotImplemented, ErrorResponse{Message: "configured weather provider cannot look up weather from a CEP-resolved city; it requires lat/lon coordinates"})
+				return
+			}
 			if err.Error() == "city not found" {
 				c.JSON(404, ErrorResponse{Message: "can not find zipcode"})
 				return
@@ -283,24 +278,89 @@ func main() {
 			return
 		}
 
-		tempF := celsiusToFahrenheit(tempC)
-		tempK := celsiusToKelvin(tempC)
+		response := observationToResponse(obs)
+
+		span.SetAttributes(
+			attribute.String("response.city", response.City),
+			attribute.Float64("response.temp_c", response.TempC),
+			attribute.Float64("response.temp_f", response.TempF),
+			attribute.Float64("response.temp_k", response.TempK),
+		)
+
+		c.JSON(200, response)
+	})
+
+	r.POST("/forecast", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tracer := otel.Tracer("service-b")
+
+		ctx, span := tracer.Start(ctx, "forecast-endpoint")
+		defer span.End()
+
+		var req ForecastRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			span.RecordError(err)
+			c.JSON(422, ErrorResponse{Message: "invalid zipcode"})
+			return
+		}
+
+		if !validateCEP(req.CEP) {
+			c.JSON(422, ErrorResponse{Message: "invalid zipcode"})
+			return
+		}
+
+		if req.Days <= 0 {
+			req.Days = 1
+		}
+
+		span.SetAttributes(
+			attribute.String("cep", req.CEP),
+			attribute.Int("forecast.days", req.Days),
+		)
+
+		forecaster, ok := weatherProvider.(weather.ForecastProvider)
+		if !ok {
+			c.JSON(http.StatusNotImplemented, ErrorResponse{Message: "configured weather provider does not support forecasts"})
+			return
+		}
+
+		city, err := cacheLayer.cityFromCEP(ctx, req.CEP)
+		if err != nil {
+			if err.Error() == "CEP not found" {
+				c.JSON(404, ErrorResponse{Message: "can not find zipcode"})
+				return
+			}
+			span.RecordError(err)
+			c.JSON(500, ErrorResponse{Message: "internal server error"})
+			return
+		}
+
+		observations, err := forecaster.Forecast(ctx, weather.Location{City: city}, req.Days)
+		if err != nil {
+			if errors.Is(err, weather.ErrCoordinatesRequired) {
+				c.JSON(http.StatusNotImplemented, ErrorResponse{Message: "configured weather provider cannot forecast from a CEP-resolved city; it requires lat/lon coordinates"})
+				return
+			}
+			if err.Error() == "city not found" {
+				c.JSON(404, ErrorResponse{Message: "can not find zipcode"})
+				return
+			}
+			span.RecordError(err)
+			c.JSON(500, ErrorResponse{Message: "internal server error"})
+			return
+		}
 
-		response := WeatherResponse{
-			City:  city,
-			TempC: tempC,
-			TempF: tempF,
-			TempK: tempK,
+		days := make([]WeatherResponse, 0, len(observations))
+		for _, obs := range observations {
+			days = append(days, observationToResponse(obs))
 		}
 
 		span.SetAttributes(
 			attribute.String("response.city", city),
-			attribute.Float64("response.temp_c", tempC),
-			attribute.Float64("response.temp_f", tempF),
-			attribute.Float64("response.temp_k", tempK),
+			attribute.Int("response.forecast_days", len(days)),
 		)
 
-		c.JSON(200, response)
+		c.JSON(200, ForecastResponse{City: city, Forecast: days})
 	})
 
 	r.Run(":8081")